@@ -0,0 +1,165 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	device "github.com/edgexfoundry/device-sdk-go"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	logger "github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+)
+
+const (
+	maxRegistersPerRead    = 125  // Modbus PDU limit for ReadHoldingRegisters/ReadInputRegisters
+	maxCoilsPerRead        = 2000 // Modbus PDU limit for ReadCoils/ReadDiscreteInputs
+	defaultReadGroupMaxGap = 10   // register/coil addresses a gap may span and still be coalesced
+)
+
+// loadReadGroupMaxGap reads ReadGroupMaxGap from the driver's custom TOML
+// configuration, falling back to defaultReadGroupMaxGap when the key is
+// absent or malformed.
+func loadReadGroupMaxGap(lc logger.LoggingClient) uint16 {
+	driverConfig, err := device.DriverConfigs()
+	if err != nil {
+		return defaultReadGroupMaxGap
+	}
+
+	v, ok := driverConfig["ReadGroupMaxGap"]
+	if !ok {
+		return defaultReadGroupMaxGap
+	}
+
+	n, convErr := strconv.ParseUint(v, 10, 16)
+	if convErr != nil {
+		lc.Warn(fmt.Sprintf("HandleReadCommands ignoring malformed ReadGroupMaxGap %q", v))
+		return defaultReadGroupMaxGap
+	}
+
+	return uint16(n)
+}
+
+// readPlanItem binds one CommandRequest back to its position in the
+// original reqs slice, along with the CommandInfo it was configured with, so
+// a batched group response can be sliced back into the original order.
+type readPlanItem struct {
+	index       int
+	req         sdkModel.CommandRequest
+	commandInfo *CommandInfo
+}
+
+// readGroup is a run of adjacent (or near-adjacent, within a configurable
+// gap) CommandRequests sharing a PrimaryTable that can be satisfied with a
+// single Modbus read transaction. A group is never mergeable once it holds a
+// resource that fails checkReadWrite, so a write-only resource always reads
+// alone through the singleton/fallback path that validates it.
+type readGroup struct {
+	primaryTable    string
+	startingAddress uint16
+	length          uint16
+	items           []readPlanItem
+	mergeable       bool
+}
+
+// planReadGroups sorts reqs' CommandInfo by PrimaryTable/StartingAddress and
+// greedily merges adjacent, readable entries into groups, never exceeding
+// the Modbus PDU limit for the table's function code. A resource that fails
+// checkReadWrite(..., "R") is excluded from coalescing and always forms its
+// own group, so it's rejected by handleReadCommandRequest's own check
+// instead of being silently folded into a batched read.
+func planReadGroups(reqs []sdkModel.CommandRequest, maxGap uint16) []readGroup {
+	items := make([]readPlanItem, len(reqs))
+	for i, req := range reqs {
+		items[i] = readPlanItem{index: i, req: req, commandInfo: createCommandInfo(req.DeviceResource)}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].commandInfo.PrimaryTable != items[j].commandInfo.PrimaryTable {
+			return items[i].commandInfo.PrimaryTable < items[j].commandInfo.PrimaryTable
+		}
+		return items[i].commandInfo.StartingAddress < items[j].commandInfo.StartingAddress
+	})
+
+	var groups []readGroup
+	for _, item := range items {
+		ci := item.commandInfo
+		end := ci.StartingAddress + ci.Length
+		limit := maxUnitsForTable(ci.PrimaryTable)
+		readable := checkReadWrite(item.req.DeviceResource, "R") == nil
+
+		if n := len(groups); n > 0 && readable {
+			g := &groups[n-1]
+			groupEnd := g.startingAddress + g.length
+			fits := end-g.startingAddress <= limit
+
+			if g.mergeable && g.primaryTable == ci.PrimaryTable && ci.StartingAddress <= groupEnd+maxGap && fits {
+				if end > groupEnd {
+					g.length = end - g.startingAddress
+				}
+				g.items = append(g.items, item)
+				continue
+			}
+		}
+
+		groups = append(groups, readGroup{
+			primaryTable:    ci.PrimaryTable,
+			startingAddress: ci.StartingAddress,
+			length:          ci.Length,
+			items:           []readPlanItem{item},
+			mergeable:       readable,
+		})
+	}
+
+	return groups
+}
+
+func maxUnitsForTable(primaryTable string) uint16 {
+	switch primaryTable {
+	case "COILS", "DISCRETES_INPUT":
+		return maxCoilsPerRead
+	default:
+		return maxRegistersPerRead
+	}
+}
+
+// isRegisterTable reports whether primaryTable addresses 16-bit registers
+// (2 bytes on the wire) as opposed to single-bit coils/discretes.
+func isRegisterTable(primaryTable string) bool {
+	return primaryTable != "COILS" && primaryTable != "DISCRETES_INPUT"
+}
+
+// sliceGroupResponse carves the bytes belonging to item out of a group's
+// combined read response.
+func sliceGroupResponse(group readGroup, item readPlanItem, response []byte) ([]byte, error) {
+	ci := item.commandInfo
+
+	if isRegisterTable(group.primaryTable) {
+		byteOffset := int(ci.StartingAddress-group.startingAddress) * 2
+		byteLength := int(ci.Length) * 2
+		if byteOffset+byteLength > len(response) {
+			return nil, fmt.Errorf("group response too short for Cmd:%v, want %v bytes at offset %v, got %v bytes", item.req.DeviceResource.Name, byteLength, byteOffset, len(response))
+		}
+		return response[byteOffset : byteOffset+byteLength], nil
+	}
+
+	// Coils/discrete inputs are bit-packed; only single-bit resources can be
+	// sliced out of a combined response, larger spans fall back per-request.
+	if ci.Length != 1 {
+		return nil, fmt.Errorf("cannot slice multi-bit resource Cmd:%v out of a batched coil read", item.req.DeviceResource.Name)
+	}
+
+	bitOffset := int(ci.StartingAddress - group.startingAddress)
+	byteIndex := bitOffset / 8
+	if byteIndex >= len(response) {
+		return nil, fmt.Errorf("group response too short for Cmd:%v, want coil bit %v, got %v bytes", item.req.DeviceResource.Name, bitOffset, len(response))
+	}
+	bit := (response[byteIndex] >> uint(bitOffset%8)) & 0x01
+
+	return []byte{bit}, nil
+}
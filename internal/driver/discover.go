@@ -0,0 +1,242 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	device "github.com/edgexfoundry/device-sdk-go"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	logger "github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+const (
+	defaultProbePrimaryTable = "HOLDING_REGISTERS"
+	defaultProbeAddress      = uint16(0)
+	defaultProbeLength       = uint16(1)
+	defaultMinUnitID         = 1
+	defaultMaxUnitID         = 247
+)
+
+// discoveryProbeConfig controls the cheap, side-effect-free read Discover
+// issues to decide whether a unit ID answers, and the unit ID range it
+// scans. Defaults probe a single holding register at address 0 across units
+// 1-247, but profiles that don't expose register 0 (or that use a different
+// function code) need these configurable per the driver's custom TOML.
+type discoveryProbeConfig struct {
+	primaryTable string
+	address      uint16
+	length       uint16
+	minUnitID    int
+	maxUnitID    int
+}
+
+func (c discoveryProbeConfig) commandInfo() *CommandInfo {
+	return &CommandInfo{
+		PrimaryTable:    c.primaryTable,
+		StartingAddress: c.address,
+		Length:          c.length,
+	}
+}
+
+// loadDiscoveryProbeConfig reads DiscoveryProbePrimaryTable,
+// DiscoveryProbeAddress, DiscoveryProbeLength, DiscoveryMinUnitID and
+// DiscoveryMaxUnitID from the driver's custom TOML configuration, falling
+// back to sane defaults when a key is absent or malformed.
+func loadDiscoveryProbeConfig(lc logger.LoggingClient) discoveryProbeConfig {
+	cfg := discoveryProbeConfig{
+		primaryTable: defaultProbePrimaryTable,
+		address:      defaultProbeAddress,
+		length:       defaultProbeLength,
+		minUnitID:    defaultMinUnitID,
+		maxUnitID:    defaultMaxUnitID,
+	}
+
+	driverConfig, err := device.DriverConfigs()
+	if err != nil {
+		return cfg
+	}
+
+	if v, ok := driverConfig["DiscoveryProbePrimaryTable"]; ok && v != "" {
+		cfg.primaryTable = v
+	}
+	if v, ok := driverConfig["DiscoveryProbeAddress"]; ok {
+		if n, convErr := strconv.ParseUint(v, 10, 16); convErr == nil {
+			cfg.address = uint16(n)
+		} else {
+			lc.Warn(fmt.Sprintf("Discover ignoring malformed DiscoveryProbeAddress %q", v))
+		}
+	}
+	if v, ok := driverConfig["DiscoveryProbeLength"]; ok {
+		if n, convErr := strconv.ParseUint(v, 10, 16); convErr == nil {
+			cfg.length = uint16(n)
+		} else {
+			lc.Warn(fmt.Sprintf("Discover ignoring malformed DiscoveryProbeLength %q", v))
+		}
+	}
+	if v, ok := driverConfig["DiscoveryMinUnitID"]; ok {
+		if n, convErr := strconv.Atoi(v); convErr == nil {
+			cfg.minUnitID = n
+		} else {
+			lc.Warn(fmt.Sprintf("Discover ignoring malformed DiscoveryMinUnitID %q", v))
+		}
+	}
+	if v, ok := driverConfig["DiscoveryMaxUnitID"]; ok {
+		if n, convErr := strconv.Atoi(v); convErr == nil {
+			cfg.maxUnitID = n
+		} else {
+			lc.Warn(fmt.Sprintf("Discover ignoring malformed DiscoveryMaxUnitID %q", v))
+		}
+	}
+
+	return cfg
+}
+
+// discoveryTarget is one configured TCP host or serial port that Discover
+// scans across every unit ID.
+type discoveryTarget struct {
+	protocol string
+	address  string
+	port     int
+}
+
+// Discover probes every configured unit ID range on every configured TCP
+// host or serial port and reports the ones that answer the configured probe
+// read as DiscoveredDevices. Wired through sdkModel.ProtocolDiscover.
+func (d *Driver) Discover() {
+	targets := loadDiscoveryTargets(d.Logger)
+	if len(targets) == 0 {
+		d.Logger.Info("Discover called but no DiscoveryTCPTargets/DiscoverySerialTargets are configured, skipping")
+		return
+	}
+
+	probeConfig := loadDiscoveryProbeConfig(d.Logger)
+
+	var discovered []sdkModel.DiscoveredDevice
+
+scan:
+	for _, target := range targets {
+		for unitID := probeConfig.minUnitID; unitID <= probeConfig.maxUnitID; unitID++ {
+			select {
+			case <-d.done:
+				d.Logger.Info("Discover interrupted by shutdown")
+				break scan
+			default:
+			}
+
+			addr := &models.Addressable{
+				Name:     fmt.Sprintf("%v-unit-%v", target.address, unitID),
+				Protocol: target.protocol,
+				Address:  target.address,
+				Port:     target.port,
+				Path:     strconv.Itoa(unitID),
+			}
+
+			lock, err := d.lockAddress(addr)
+			if err != nil {
+				d.Logger.Warn(fmt.Sprintf("Discover could not lock %v, skipping. err:%v", addr.Name, err))
+				continue
+			}
+
+			found := d.probeAddress(addr, probeConfig)
+			// release the lock as soon as probing completes, before the next
+			// unit ID is probed, so discovery doesn't block regular commands
+			d.unlockAddress(addr, lock)
+
+			if found {
+				discovered = append(discovered, sdkModel.DiscoveredDevice{
+					Name:        addr.Name,
+					Addressable: *addr,
+				})
+			}
+		}
+	}
+
+	d.Logger.Info(fmt.Sprintf("Discover finished, found %v device(s)", len(discovered)))
+
+	if len(discovered) > 0 {
+		device.RunningService().AddDiscoveredDevices(discovered)
+	}
+}
+
+// probeAddress dials addr through the client pool and issues the configured
+// cheap read to decide whether a Modbus unit answers at this address.
+func (d *Driver) probeAddress(addr *models.Addressable, probeConfig discoveryProbeConfig) bool {
+	connectionInfo, err := createConnectionInfo(*addr)
+	if err != nil {
+		return false
+	}
+
+	client, err := d.clientPool.Get(connectionInfo)
+	if err != nil {
+		return false
+	}
+
+	_, err = client.GetValue(probeConfig.commandInfo())
+	d.clientPool.Release(connectionInfo, client, err != nil)
+
+	return err == nil
+}
+
+// loadDiscoveryTargets reads DiscoveryTCPTargets ("host:port,host:port") and
+// DiscoverySerialTargets ("/dev/ttyUSB0:19200,...") from the driver's custom
+// TOML configuration.
+func loadDiscoveryTargets(lc logger.LoggingClient) []discoveryTarget {
+	var targets []discoveryTarget
+
+	driverConfig, err := device.DriverConfigs()
+	if err != nil {
+		return targets
+	}
+
+	for _, entry := range splitNonEmpty(driverConfig["DiscoveryTCPTargets"]) {
+		host, portStr, ok := splitLast(entry, ":")
+		port, convErr := strconv.Atoi(portStr)
+		if !ok || convErr != nil {
+			lc.Warn(fmt.Sprintf("Discover ignoring malformed DiscoveryTCPTargets entry %q", entry))
+			continue
+		}
+		targets = append(targets, discoveryTarget{protocol: "TCP", address: host, port: port})
+	}
+
+	for _, entry := range splitNonEmpty(driverConfig["DiscoverySerialTargets"]) {
+		path, baudStr, ok := splitLast(entry, ":")
+		baud, convErr := strconv.Atoi(baudStr)
+		if !ok || convErr != nil {
+			lc.Warn(fmt.Sprintf("Discover ignoring malformed DiscoverySerialTargets entry %q", entry))
+			continue
+		}
+		targets = append(targets, discoveryTarget{protocol: "RTU", address: path, port: baud})
+	}
+
+	return targets
+}
+
+func splitNonEmpty(csv string) []string {
+	if csv == "" {
+		return nil
+	}
+
+	var result []string
+	for _, part := range strings.Split(csv, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+func splitLast(s, sep string) (string, string, bool) {
+	idx := strings.LastIndex(s, sep)
+	if idx < 0 {
+		return "", "", false
+	}
+	return s[:idx], s[idx+1:], true
+}
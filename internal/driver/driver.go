@@ -10,6 +10,7 @@ package driver
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	sdkModel "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	logger "github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
@@ -25,16 +26,43 @@ type Driver struct {
 	mutex               sync.Mutex
 	addressMap          map[string]chan bool
 	workingAddressCount map[string]int
+	clientPool          *ClientPool
+	publishConfig       publishAfterWriteConfig
+	readGroupMaxGap     uint16
+	done                chan struct{}
+	stopped             bool
 }
 
 var concurrentCommandLimit = 100
 
-func (*Driver) DisconnectDevice(address *models.Addressable) error {
-	panic("implement me")
+// stopDrainTimeout bounds how long a graceful Stop waits for in-flight
+// lockAddress holders to finish before tearing down the pool regardless.
+const stopDrainTimeout = 30 * time.Second
+const stopDrainPollInterval = 100 * time.Millisecond
+
+// DisconnectDevice closes the pooled client(s) bound to address and removes
+// its address lock entry, without affecting any other device's connections.
+func (d *Driver) DisconnectDevice(address *models.Addressable) error {
+	connectionInfo, err := createConnectionInfo(*address)
+	if err != nil {
+		return err
+	}
+
+	d.clientPool.CloseForKey(connectionInfo)
+
+	d.mutex.Lock()
+	delete(d.addressMap, address.Address)
+	delete(d.workingAddressCount, address.Address)
+	d.mutex.Unlock()
+
+	return nil
 }
 
-// lockAddress mark address is unavailable because real device handle one request at a time
-func (d *Driver) lockAddress(address *models.Addressable) error {
+// lockAddress mark address is unavailable because real device handle one request at a time.
+// It returns the lock channel the caller acquired so unlockAddress can release that exact
+// channel later, rather than re-reading d.addressMap[address.Address] after the command
+// finishes — DisconnectDevice/Stop may have deleted or replaced that entry by then.
+func (d *Driver) lockAddress(address *models.Addressable) (chan bool, error) {
 	d.mutex.Lock()
 	lock, ok := d.addressMap[address.Address]
 	if !ok {
@@ -50,7 +78,7 @@ func (d *Driver) lockAddress(address *models.Addressable) error {
 		d.mutex.Unlock()
 		errorMessage := fmt.Sprintf("High-frequency command execution. There are %v commands with the same address in the queue", concurrentCommandLimit)
 		d.Logger.Warn(errorMessage)
-		return fmt.Errorf(errorMessage)
+		return nil, fmt.Errorf(errorMessage)
 	} else {
 		d.workingAddressCount[address.Address] = d.workingAddressCount[address.Address] + 1
 	}
@@ -58,27 +86,31 @@ func (d *Driver) lockAddress(address *models.Addressable) error {
 	d.mutex.Unlock()
 	lock <- true
 
-	return nil
+	return lock, nil
 }
 
-// unlockAddress remove token after command finish
-func (d *Driver) unlockAddress(address *models.Addressable) {
+// unlockAddress remove token after command finish. lock is the channel returned by the
+// matching lockAddress call; it is released directly instead of being looked up again by
+// address so a concurrent DisconnectDevice/Stop that removed the address entry can't leave
+// this receive blocked on a nil map lookup.
+func (d *Driver) unlockAddress(address *models.Addressable, lock chan bool) {
 	d.mutex.Lock()
-	lock := d.addressMap[address.Address]
-	d.workingAddressCount[address.Address] = d.workingAddressCount[address.Address] - 1
+	if count, ok := d.workingAddressCount[address.Address]; ok {
+		d.workingAddressCount[address.Address] = count - 1
+	}
 	d.mutex.Unlock()
+
 	<-lock
 }
 
 func (d *Driver) HandleReadCommands(addr *models.Addressable, reqs []sdkModel.CommandRequest) (responses []*sdkModel.CommandValue, err error) {
-	err = d.lockAddress(addr)
+	lock, err := d.lockAddress(addr)
 	if err != nil {
 		return responses, err
 	}
-	defer d.unlockAddress(addr)
+	defer d.unlockAddress(addr, lock)
 
 	responses = make([]*sdkModel.CommandValue, len(reqs))
-	var deviceClient DeviceClient
 
 	// Check request's attribute to avoid interface cast error
 	err = checkAttributes(reqs)
@@ -87,39 +119,88 @@ func (d *Driver) HandleReadCommands(addr *models.Addressable, reqs []sdkModel.Co
 		return responses, err
 	}
 
-	// create device client and open connection
+	// fetch a pooled device client, dialing a new one if none is cached
 	connectionInfo, err := createConnectionInfo(*addr)
 	if err != nil {
 		driver.Logger.Info(fmt.Sprintf("Read command createConnectionInfo failed. err:%v \n", err))
 		return responses, err
 	}
 
-	deviceClient, err = NewDeviceClient(connectionInfo)
+	deviceClient, err := d.clientPool.Get(connectionInfo)
 	if err != nil {
-		driver.Logger.Info(fmt.Sprintf("Read command NewDeviceClient failed. err:%v \n", err))
+		driver.Logger.Info(fmt.Sprintf("Read command failed to obtain a pooled client. err:%v \n", err))
 		return responses, err
 	}
 
-	err = deviceClient.OpenConnection()
+	broken := false
+	defer func() { d.clientPool.Release(connectionInfo, deviceClient, broken) }()
+
+	// coalesce adjacent reads sharing a function code into a single Modbus
+	// transaction, then fall back per-request on a group read failure so one
+	// bad register doesn't poison the whole batch
+	for _, group := range planReadGroups(reqs, d.readGroupMaxGap) {
+		select {
+		case <-d.done:
+			return responses, fmt.Errorf("driver is shutting down")
+		default:
+		}
+
+		if len(group.items) > 1 {
+			if groupErr := d.handleGroupedReadCommandRequest(deviceClient, group, responses); groupErr == nil {
+				continue
+			} else {
+				driver.Logger.Info(fmt.Sprintf("Batched read of %v[%v:%v] failed, falling back to per-request reads. err:%v \n", group.primaryTable, group.startingAddress, group.length, groupErr))
+			}
+		}
+
+		for _, item := range group.items {
+			res, itemErr := d.handleReadCommandRequest(deviceClient, item.req)
+			if itemErr != nil {
+				driver.Logger.Info(fmt.Sprintf("Read command failed. Cmd:%v err:%v \n", item.req.DeviceResource.Name, itemErr))
+				broken = isTransportError(itemErr)
+				return responses, itemErr
+			}
+
+			responses[item.index] = res
+		}
+	}
+
+	return responses, nil
+}
+
+// handleGroupedReadCommandRequest issues a single Modbus read spanning
+// group's address range and slices the response back into responses at each
+// item's original index.
+func (d *Driver) handleGroupedReadCommandRequest(deviceClient DeviceClient, group readGroup, responses []*sdkModel.CommandValue) error {
+	combined := *group.items[0].commandInfo
+	combined.StartingAddress = group.startingAddress
+	combined.Length = group.length
+
+	response, err := deviceClient.GetValue(&combined)
 	if err != nil {
-		driver.Logger.Info(fmt.Sprintf("Read command OpenConnection failed. err:%v \n", err))
-		return responses, err
+		return err
 	}
 
-	defer deviceClient.CloseConnection()
+	for _, item := range group.items {
+		if err := checkReadWrite(item.req.DeviceResource, "R"); err != nil {
+			return err
+		}
+
+		sub, err := sliceGroupResponse(group, item, response)
+		if err != nil {
+			return err
+		}
 
-	// handle command requests
-	for i, req := range reqs {
-		res, err := d.handleReadCommandRequest(deviceClient, req)
+		result, err := TransformDateBytesToResult(&item.req.RO, sub, item.commandInfo)
 		if err != nil {
-			driver.Logger.Info(fmt.Sprintf("Read command failed. Cmd:%v err:%v \n", req.DeviceResource.Name, err))
-			return responses, err
+			return err
 		}
 
-		responses[i] = res
+		driver.Logger.Info(fmt.Sprintf("Read command finished. Cmd:%v, %v \n", item.req.DeviceResource.Name, result))
+		responses[item.index] = result
 	}
 
-	return responses, nil
+	return nil
 }
 
 func (d *Driver) handleReadCommandRequest(deviceClient DeviceClient, req sdkModel.CommandRequest) (*sdkModel.CommandValue, error) {
@@ -127,6 +208,10 @@ func (d *Driver) handleReadCommandRequest(deviceClient DeviceClient, req sdkMode
 	var result = &sdkModel.CommandValue{}
 	var err error
 
+	if err = checkReadWrite(req.DeviceResource, "R"); err != nil {
+		return result, err
+	}
+
 	commandInfo := createCommandInfo(req.DeviceResource)
 
 	response, err = deviceClient.GetValue(commandInfo)
@@ -147,13 +232,11 @@ func (d *Driver) handleReadCommandRequest(deviceClient DeviceClient, req sdkMode
 }
 
 func (d *Driver) HandleWriteCommands(addr *models.Addressable, reqs []sdkModel.CommandRequest, params []*sdkModel.CommandValue) error {
-	err := d.lockAddress(addr)
+	lock, err := d.lockAddress(addr)
 	if err != nil {
 		return err
 	}
-	defer d.unlockAddress(addr)
-
-	var deviceClient DeviceClient
+	defer d.unlockAddress(addr, lock)
 
 	// Check request's attribute to avoid interface cast error
 	err = checkAttributes(reqs)
@@ -162,29 +245,26 @@ func (d *Driver) HandleWriteCommands(addr *models.Addressable, reqs []sdkModel.C
 		return err
 	}
 
-	// create device client and open connection
+	// fetch a pooled device client, dialing a new one if none is cached
 	connectionInfo, err := createConnectionInfo(*addr)
 	if err != nil {
 		return err
 	}
 
-	deviceClient, err = NewDeviceClient(connectionInfo)
+	deviceClient, err := d.clientPool.Get(connectionInfo)
 	if err != nil {
 		return err
 	}
 
-	err = deviceClient.OpenConnection()
-	if err != nil {
-		return err
-	}
-
-	defer deviceClient.CloseConnection()
+	broken := false
+	defer func() { d.clientPool.Release(connectionInfo, deviceClient, broken) }()
 
 	// handle command requests
 	for i, req := range reqs {
-		err = d.handleWriteCommandRequest(deviceClient, req, params[i])
+		err = d.handleWriteCommandRequest(deviceClient, addr, req, params[i])
 		if err != nil {
 			d.Logger.Error(err.Error())
+			broken = isTransportError(err)
 			break
 		}
 	}
@@ -192,9 +272,13 @@ func (d *Driver) HandleWriteCommands(addr *models.Addressable, reqs []sdkModel.C
 	return err
 }
 
-func (d *Driver) handleWriteCommandRequest(deviceClient DeviceClient, req sdkModel.CommandRequest, param *sdkModel.CommandValue) error {
+func (d *Driver) handleWriteCommandRequest(deviceClient DeviceClient, addr *models.Addressable, req sdkModel.CommandRequest, param *sdkModel.CommandValue) error {
 	var err error
 
+	if err = checkReadWrite(req.DeviceResource, "W"); err != nil {
+		return err
+	}
+
 	commandInfo := createCommandInfo(req.DeviceResource)
 
 	dataBytes, err := TransformCommandValueToDataBytes(commandInfo, param)
@@ -208,6 +292,8 @@ func (d *Driver) handleWriteCommandRequest(deviceClient DeviceClient, req sdkMod
 	}
 
 	driver.Logger.Info(fmt.Sprintf("Write command finished. Cmd:%v \n", req.DeviceResource.Name))
+
+	d.publishWrittenValue(deviceClient, addr, req, param)
 	return nil
 }
 
@@ -216,11 +302,66 @@ func (d *Driver) Initialize(lc logger.LoggingClient, asyncCh chan<- *sdkModel.As
 	d.AsyncCh = asyncCh
 	d.addressMap = make(map[string]chan bool)
 	d.workingAddressCount = make(map[string]int)
+	d.clientPool = NewClientPool(lc)
+	d.publishConfig = loadPublishAfterWriteConfig(lc)
+	d.readGroupMaxGap = loadReadGroupMaxGap(lc)
+	d.done = make(chan struct{})
+	return nil
+}
+
+// Stop signals shutdown via d.done so long-running reads can observe it,
+// then tears down addressMap/workingAddressCount and closes every pooled
+// client. Unless force is set, it first waits up to stopDrainTimeout for
+// in-flight lockAddress holders to finish so they aren't cut off mid-command.
+func (d *Driver) Stop(force bool) error {
+	d.mutex.Lock()
+	if d.stopped {
+		d.mutex.Unlock()
+		return nil
+	}
+	d.stopped = true
+	d.mutex.Unlock()
+
+	close(d.done)
+
+	if !force {
+		d.drainInFlightCommands(stopDrainTimeout)
+	}
+
+	d.clientPool.Close()
+
+	d.mutex.Lock()
+	d.addressMap = make(map[string]chan bool)
+	d.workingAddressCount = make(map[string]int)
+	d.mutex.Unlock()
+
 	return nil
 }
 
-func (*Driver) Stop(force bool) error {
-	panic("implement me")
+// drainInFlightCommands blocks until no address has any in-flight command
+// or timeout elapses, whichever comes first.
+func (d *Driver) drainInFlightCommands(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		if d.inFlightCommandCount() == 0 {
+			return
+		}
+		time.Sleep(stopDrainPollInterval)
+	}
+
+	d.Logger.Warn("Stop timed out waiting for in-flight commands to drain")
+}
+
+func (d *Driver) inFlightCommandCount() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	total := 0
+	for _, count := range d.workingAddressCount {
+		total += count
+	}
+	return total
 }
 
 func NewProtocolDriver() sdkModel.ProtocolDriver {
@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// NotAllowedError is returned when a CommandRequest's direction conflicts
+// with the ReadWrite attribute configured on the target DeviceResource, e.g.
+// a write issued against a resource declared "R".
+type NotAllowedError struct {
+	ResourceName string
+	ReadWrite    string
+	Requested    string
+}
+
+func (e *NotAllowedError) Error() string {
+	return fmt.Sprintf("%v command not allowed on resource %v, configured ReadWrite is %q", e.Requested, e.ResourceName, e.ReadWrite)
+}
+
+// checkReadWrite returns a *NotAllowedError if resource's configured
+// ReadWrite attribute does not permit the requested direction ("R" or "W").
+// An empty ReadWrite attribute is treated as "RW" for backward compatibility
+// with profiles that predate this attribute.
+func checkReadWrite(resource models.DeviceResource, requested string) error {
+	readWrite := resource.Properties.Value.ReadWrite
+	if readWrite == "" || readWrite == "RW" || readWrite == requested {
+		return nil
+	}
+
+	return &NotAllowedError{
+		ResourceName: resource.Name,
+		ReadWrite:    readWrite,
+		Requested:    requested,
+	}
+}
+
+// isTransportError reports whether err should be treated as a Modbus
+// transport failure worth discarding a pooled connection over. Every non-nil
+// error counts as one except *NotAllowedError, which never touched the wire
+// and leaves the connection perfectly healthy; other validation or transform
+// failures are not told apart from I/O failures.
+func isTransportError(err error) bool {
+	var notAllowed *NotAllowedError
+	return err != nil && !errors.As(err, &notAllowed)
+}
@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	device "github.com/edgexfoundry/device-sdk-go"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	logger "github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// publishAfterWriteConfig controls whether HandleWriteCommands publishes an
+// AsyncValues event for a successful write, mirroring the upstream
+// device-sdk-go PUT-then-event behavior.
+type publishAfterWriteConfig struct {
+	enabled  bool
+	readback bool
+	devices  map[string]bool
+}
+
+// loadPublishAfterWriteConfig reads PublishAfterWrite, PublishAfterWriteReadback
+// and PublishAfterWriteDevices from the driver's custom TOML configuration.
+// PublishAfterWriteDevices is a comma-separated allow-list; when empty the
+// global PublishAfterWrite flag applies to every device.
+func loadPublishAfterWriteConfig(lc logger.LoggingClient) publishAfterWriteConfig {
+	cfg := publishAfterWriteConfig{devices: make(map[string]bool)}
+
+	driverConfig, err := device.DriverConfigs()
+	if err != nil {
+		return cfg
+	}
+
+	if v, ok := driverConfig["PublishAfterWrite"]; ok {
+		if b, convErr := strconv.ParseBool(v); convErr == nil {
+			cfg.enabled = b
+		} else {
+			lc.Warn(fmt.Sprintf("PublishAfterWrite config value %q is not a bool, defaulting to false", v))
+		}
+	}
+
+	if v, ok := driverConfig["PublishAfterWriteReadback"]; ok {
+		if b, convErr := strconv.ParseBool(v); convErr == nil {
+			cfg.readback = b
+		}
+	}
+
+	if v, ok := driverConfig["PublishAfterWriteDevices"]; ok && v != "" {
+		for _, name := range strings.Split(v, ",") {
+			cfg.devices[strings.TrimSpace(name)] = true
+		}
+	}
+
+	return cfg
+}
+
+// shouldPublish reports whether a successful write against deviceName should
+// be published as an AsyncValues event.
+func (c publishAfterWriteConfig) shouldPublish(deviceName string) bool {
+	if len(c.devices) > 0 {
+		return c.devices[deviceName]
+	}
+	return c.enabled
+}
+
+// publishWrittenValue emits an AsyncValues event on d.AsyncCh carrying the
+// value that was just written, so northbound consumers don't have to poll
+// after every actuation. When publishConfig.readback is set, it re-reads the
+// resource via GetValue for every write, whatever the function code, and
+// publishes that instead of the request's CommandValue; if the readback
+// fails, the written value is published.
+func (d *Driver) publishWrittenValue(deviceClient DeviceClient, addr *models.Addressable, req sdkModel.CommandRequest, written *sdkModel.CommandValue) {
+	if !d.publishConfig.shouldPublish(addr.Name) {
+		return
+	}
+
+	value := written
+
+	if d.publishConfig.readback {
+		commandInfo := createCommandInfo(req.DeviceResource)
+		response, err := deviceClient.GetValue(commandInfo)
+		if err != nil {
+			d.Logger.Warn(fmt.Sprintf("PublishAfterWrite readback failed for Cmd:%v, publishing written value instead. err:%v", req.DeviceResource.Name, err))
+		} else if result, transformErr := TransformDateBytesToResult(&req.RO, response, commandInfo); transformErr == nil {
+			value = result
+		} else {
+			d.Logger.Warn(fmt.Sprintf("PublishAfterWrite readback transform failed for Cmd:%v, publishing written value instead. err:%v", req.DeviceResource.Name, transformErr))
+		}
+	}
+
+	d.AsyncCh <- &sdkModel.AsyncValues{
+		DeviceName:    addr.Name,
+		CommandValues: []*sdkModel.CommandValue{value},
+	}
+}
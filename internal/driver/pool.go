@@ -0,0 +1,268 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	device "github.com/edgexfoundry/device-sdk-go"
+	logger "github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+)
+
+const (
+	defaultPoolMaxIdlePerKey  = 5
+	defaultPoolIdleTimeout    = 5 * time.Minute
+	defaultReconnectBaseDelay = 250 * time.Millisecond
+	defaultReconnectMaxDelay  = 30 * time.Second
+	defaultReconnectRetries   = 5
+)
+
+// pooledClient wraps a DeviceClient with the bookkeeping ClientPool needs to
+// decide how long the connection has been sitting idle.
+type pooledClient struct {
+	client   DeviceClient
+	key      string
+	lastUsed time.Time
+}
+
+// ClientPool keeps long-lived DeviceClients around so that
+// HandleReadCommands and HandleWriteCommands don't pay the cost of a fresh
+// TCP handshake (Modbus TCP) or serial port re-open (Modbus RTU) on every
+// command batch. Clients are cached by connection info and evicted after
+// sitting idle past idleTimeout. A client is dropped only when its caller
+// releases it as broken; cached clients are not probed before reuse.
+type ClientPool struct {
+	logger logger.LoggingClient
+
+	mutex     sync.Mutex
+	idle      map[string][]*pooledClient
+	stopped   bool
+	closeDone chan struct{}
+
+	idleTimeout    time.Duration
+	maxIdlePerKey  int
+	reconnectBase  time.Duration
+	reconnectMax   time.Duration
+	reconnectTries int
+}
+
+// NewClientPool builds a ClientPool, reading PoolMaxIdlePerKey,
+// PoolIdleTimeoutSeconds, ReconnectBaseDelayMilliseconds,
+// ReconnectMaxDelaySeconds and ReconnectRetries from the driver's custom
+// TOML configuration when present, falling back to sane defaults otherwise.
+func NewClientPool(lc logger.LoggingClient) *ClientPool {
+	pool := &ClientPool{
+		logger:         lc,
+		idle:           make(map[string][]*pooledClient),
+		closeDone:      make(chan struct{}),
+		idleTimeout:    defaultPoolIdleTimeout,
+		maxIdlePerKey:  defaultPoolMaxIdlePerKey,
+		reconnectBase:  defaultReconnectBaseDelay,
+		reconnectMax:   defaultReconnectMaxDelay,
+		reconnectTries: defaultReconnectRetries,
+	}
+
+	if driverConfig, err := device.DriverConfigs(); err == nil {
+		if v, ok := driverConfig["PoolMaxIdlePerKey"]; ok {
+			if n, convErr := strconv.Atoi(v); convErr == nil {
+				pool.maxIdlePerKey = n
+			}
+		}
+		if v, ok := driverConfig["PoolIdleTimeoutSeconds"]; ok {
+			if n, convErr := strconv.Atoi(v); convErr == nil {
+				pool.idleTimeout = time.Duration(n) * time.Second
+			}
+		}
+		if v, ok := driverConfig["ReconnectBaseDelayMilliseconds"]; ok {
+			if n, convErr := strconv.Atoi(v); convErr == nil {
+				pool.reconnectBase = time.Duration(n) * time.Millisecond
+			}
+		}
+		if v, ok := driverConfig["ReconnectMaxDelaySeconds"]; ok {
+			if n, convErr := strconv.Atoi(v); convErr == nil {
+				pool.reconnectMax = time.Duration(n) * time.Second
+			}
+		}
+		if v, ok := driverConfig["ReconnectRetries"]; ok {
+			if n, convErr := strconv.Atoi(v); convErr == nil {
+				pool.reconnectTries = n
+			}
+		}
+	}
+
+	// A zero or negative PoolIdleTimeoutSeconds means "disable eviction"; feeding
+	// it straight to time.NewTicker would panic the background goroutine instead.
+	if pool.idleTimeout > 0 {
+		go pool.evictIdleLoop()
+	} else {
+		lc.Info("ClientPool idle eviction disabled (PoolIdleTimeoutSeconds <= 0)")
+	}
+
+	return pool
+}
+
+// connectionKey derives a stable cache key from a ConnectionInfo so that TCP
+// connections are keyed by IP:port/unit-id and serial connections are keyed
+// by path/baud/parity/unit-id.
+func connectionKey(connectionInfo ConnectionInfo) string {
+	return fmt.Sprintf("%+v", connectionInfo)
+}
+
+// Get returns an already-open, idle DeviceClient for connectionInfo if one is
+// cached, otherwise it dials a new one with exponential-backoff retries.
+func (p *ClientPool) Get(connectionInfo ConnectionInfo) (DeviceClient, error) {
+	key := connectionKey(connectionInfo)
+
+	p.mutex.Lock()
+	if clients := p.idle[key]; len(clients) > 0 {
+		pc := clients[len(clients)-1]
+		p.idle[key] = clients[:len(clients)-1]
+		p.mutex.Unlock()
+		return pc.client, nil
+	}
+	p.mutex.Unlock()
+
+	return p.dial(key, connectionInfo)
+}
+
+// dial opens a new DeviceClient, retrying with exponential backoff so that a
+// transient TCP handshake or serial port failure doesn't immediately bubble
+// up to the command handler.
+func (p *ClientPool) dial(key string, connectionInfo ConnectionInfo) (DeviceClient, error) {
+	delay := p.reconnectBase
+	var lastErr error
+
+	for attempt := 0; attempt <= p.reconnectTries; attempt++ {
+		if attempt > 0 {
+			p.logger.Info(fmt.Sprintf("ClientPool retrying connection to %v in %v (attempt %d/%d)", key, delay, attempt, p.reconnectTries))
+			time.Sleep(delay)
+			delay *= 2
+			if delay > p.reconnectMax {
+				delay = p.reconnectMax
+			}
+		}
+
+		client, err := NewDeviceClient(connectionInfo)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if err = client.OpenConnection(); err != nil {
+			lastErr = err
+			continue
+		}
+
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("ClientPool failed to connect to %v after %d attempts, err: %v", key, p.reconnectTries+1, lastErr)
+}
+
+// Release returns client to the pool for reuse, unless broken is true (the
+// caller hit an I/O error while using it) in which case the connection is
+// closed and dropped instead of being cached.
+func (p *ClientPool) Release(connectionInfo ConnectionInfo, client DeviceClient, broken bool) {
+	key := connectionKey(connectionInfo)
+
+	if broken {
+		client.CloseConnection()
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.stopped || len(p.idle[key]) >= p.maxIdlePerKey {
+		p.mutex.Unlock()
+		client.CloseConnection()
+		p.mutex.Lock()
+		return
+	}
+
+	p.idle[key] = append(p.idle[key], &pooledClient{client: client, key: key, lastUsed: time.Now()})
+}
+
+// CloseForKey closes and drops every idle client cached for connectionInfo.
+// Used by Driver.DisconnectDevice so a removed device's connections don't
+// linger in the pool.
+func (p *ClientPool) CloseForKey(connectionInfo ConnectionInfo) {
+	key := connectionKey(connectionInfo)
+
+	p.mutex.Lock()
+	clients := p.idle[key]
+	delete(p.idle, key)
+	p.mutex.Unlock()
+
+	for _, pc := range clients {
+		pc.client.CloseConnection()
+	}
+}
+
+// evictIdleLoop periodically closes and drops cached clients that have been
+// idle longer than idleTimeout.
+func (p *ClientPool) evictIdleLoop() {
+	ticker := time.NewTicker(p.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.evictIdle()
+		case <-p.closeDone:
+			return
+		}
+	}
+}
+
+func (p *ClientPool) evictIdle() {
+	cutoff := time.Now().Add(-p.idleTimeout)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for key, clients := range p.idle {
+		fresh := clients[:0]
+		for _, pc := range clients {
+			if pc.lastUsed.Before(cutoff) {
+				pc.client.CloseConnection()
+			} else {
+				fresh = append(fresh, pc)
+			}
+		}
+		if len(fresh) == 0 {
+			delete(p.idle, key)
+		} else {
+			p.idle[key] = fresh
+		}
+	}
+}
+
+// Close closes every pooled client and stops the eviction loop. Safe to call
+// more than once.
+func (p *ClientPool) Close() {
+	p.mutex.Lock()
+	if p.stopped {
+		p.mutex.Unlock()
+		return
+	}
+	p.stopped = true
+	idle := p.idle
+	p.idle = make(map[string][]*pooledClient)
+	p.mutex.Unlock()
+
+	for _, clients := range idle {
+		for _, pc := range clients {
+			pc.client.CloseConnection()
+		}
+	}
+
+	close(p.closeDone)
+}